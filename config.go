@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const defaultTTL = 300
+
+// HostnameRule describes one DNS name this controller keeps in sync with
+// a set of matching nodes.
+type HostnameRule struct {
+	Zone         string `yaml:"zone"`
+	Name         string `yaml:"name"`
+	Type         string `yaml:"type,omitempty"`
+	TTL          int    `yaml:"ttl,omitempty"`
+	NodeSelector string `yaml:"nodeSelector,omitempty"`
+	AddressType  string `yaml:"addressType,omitempty"`
+}
+
+// Config is the structured, on-disk configuration for this controller. It
+// supersedes the flat DNS_NAMES/NODE_SELECTOR env vars, which are still
+// honored as a fallback via defaultConfig when no config file is set.
+type Config struct {
+	Rules []HostnameRule `yaml:"rules"`
+}
+
+// defaultConfig builds a Config from the legacy DNS_NAMES/NODE_SELECTOR
+// env vars, one rule per name, all against the original hard-coded zone.
+// It exists so deployments that predate the config file keep working.
+func defaultConfig(dnsNamesEnv, nodeSelectorEnv string) (*Config, error) {
+	var cfg Config
+	for _, name := range strings.Split(dnsNamesEnv, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		cfg.Rules = append(cfg.Rules, HostnameRule{
+			Zone:         zone,
+			Name:         name,
+			NodeSelector: nodeSelectorEnv,
+		})
+	}
+
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("no DNS names configured: set DNS_NAMES or CONFIG_FILE")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadConfig reads and validates the YAML config at path.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate rejects rules missing required fields and fills in defaults
+// (TTL) on the remaining ones.
+func (c *Config) Validate() error {
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Zone == "" {
+			return fmt.Errorf("rule %d (%s): zone is required", i, rule.Name)
+		}
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i)
+		}
+		switch rule.Type {
+		case "", "A", "AAAA":
+		default:
+			return fmt.Errorf("rule %d (%s): type must be A, AAAA or empty, got %q", i, rule.Name, rule.Type)
+		}
+		switch rule.AddressType {
+		case "", "external", "internal":
+		default:
+			return fmt.Errorf("rule %d (%s): addressType must be external, internal or empty, got %q", i, rule.Name, rule.AddressType)
+		}
+		if rule.TTL == 0 {
+			rule.TTL = defaultTTL
+		}
+	}
+
+	return nil
+}
+
+// zones returns the distinct zones referenced by c's rules, used to match
+// Service/Ingress hostnames (which don't carry their own zone) to one of
+// them.
+func (c *Config) zones() []string {
+	seen := map[string]bool{}
+	var zones []string
+	for _, rule := range c.Rules {
+		if !seen[rule.Zone] {
+			seen[rule.Zone] = true
+			zones = append(zones, rule.Zone)
+		}
+	}
+	return zones
+}
+
+// selector parses the rule's NodeSelector, defaulting to every node.
+func (r HostnameRule) selector() (labels.Selector, error) {
+	if r.NodeSelector == "" {
+		return labels.Everything(), nil
+	}
+	return labels.Parse(r.NodeSelector)
+}
+
+// nodeAddressType returns the node address type this rule publishes,
+// defaulting to external.
+func (r HostnameRule) nodeAddressType() core_v1.NodeAddressType {
+	if r.AddressType == "internal" {
+		return core_v1.NodeInternalIP
+	}
+	return core_v1.NodeExternalIP
+}
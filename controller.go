@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/ineentho/kubernetes-gandi-sync/metrics"
+	"github.com/ineentho/kubernetes-gandi-sync/provider"
+	"github.com/ineentho/kubernetes-gandi-sync/registry"
+)
+
+// runController runs the Node/Service/Ingress informers and the sync
+// workqueue until stop is closed. It's the unit leader election starts
+// and stops as this replica gains and loses the lease.
+func runController(stop <-chan struct{}, client kubernetes.Interface, cfg *Config, txtRegistry *registry.TXTRegistry, health *metrics.Health) {
+	factory := informers.NewSharedInformerFactory(client, time.Minute)
+	nodeLister := factory.Core().V1().Nodes().Lister()
+	serviceLister := factory.Core().V1().Services().Lister()
+	ingressLister := factory.Networking().V1().Ingresses().Lister()
+
+	zones := cfg.zones()
+
+	resync := func() (err error) {
+		log.Debug().Msg("resyncing")
+
+		start := time.Now()
+		defer func() {
+			metrics.SyncDuration.Observe(time.Since(start).Seconds())
+			if err != nil {
+				metrics.SyncTotal.WithLabelValues("error").Inc()
+				return
+			}
+			metrics.SyncTotal.WithLabelValues("success").Inc()
+			metrics.LastSuccessfulSync.Set(float64(time.Now().Unix()))
+			health.MarkSynced()
+		}()
+
+		var records []registry.ManagedRecord
+
+		for _, rule := range cfg.Rules {
+			selector, err := rule.selector()
+			if err != nil {
+				log.Error().Str("rule", rule.Name).Err(err).Msg("invalid node selector, skipping rule")
+				continue
+			}
+
+			nodes, err := nodeLister.List(selector)
+			if err != nil {
+				return fmt.Errorf("failed to list nodes for rule %s: %w", rule.Name, err)
+			}
+
+			var addresses []string
+			for _, node := range nodes {
+				if !nodeIsReady(node) {
+					continue
+				}
+				for _, addr := range node.Status.Addresses {
+					if addr.Type == rule.nodeAddressType() {
+						addresses = append(addresses, addr.Address)
+					}
+				}
+			}
+			sort.Strings(addresses)
+
+			resource := fmt.Sprintf("node/%s", selectorHash(rule.NodeSelector))
+			records = append(records, recordsForRule(rule, addresses, resource)...)
+		}
+
+		// NodePort services are reachable on every node in the cluster
+		// regardless of any rule's NodeSelector, so their address set is
+		// gathered independently of the per-rule union above.
+		allNodes, err := nodeLister.List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+		allNodeIPSet := map[string]struct{}{}
+		for _, node := range allNodes {
+			if !nodeIsReady(node) {
+				continue
+			}
+			for _, addr := range node.Status.Addresses {
+				if addr.Type == core_v1.NodeExternalIP {
+					allNodeIPSet[addr.Address] = struct{}{}
+				}
+			}
+		}
+		allNodeIPs := make([]string, 0, len(allNodeIPSet))
+		for ip := range allNodeIPSet {
+			allNodeIPs = append(allNodeIPs, ip)
+		}
+		sort.Strings(allNodeIPs)
+
+		services, err := serviceLister.List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+
+		ingresses, err := ingressLister.List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list ingresses: %w", err)
+		}
+
+		records = append(records, serviceAndIngressRecords(zones, allNodeIPs, services, ingresses)...)
+
+		metrics.ManagedRecords.Set(float64(len(records)))
+		published := map[string]int{}
+		for _, rec := range records {
+			if rec.Type == provider.A || rec.Type == provider.AAAA {
+				published[rec.Name] += len(rec.Values)
+			}
+		}
+		for name, count := range published {
+			metrics.PublishedIPs.WithLabelValues(name).Set(float64(count))
+		}
+
+		return sync(context.Background(), txtRegistry, records)
+	}
+
+	queue := newSyncQueue()
+	defer queue.ShutDown()
+
+	handlers := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { enqueueSync(queue) },
+		UpdateFunc: func(oldObj, newObj interface{}) { enqueueSync(queue) },
+		DeleteFunc: func(obj interface{}) { enqueueSync(queue) },
+	}
+	factory.Core().V1().Nodes().Informer().AddEventHandler(handlers)
+	factory.Core().V1().Services().Informer().AddEventHandler(handlers)
+	factory.Networking().V1().Ingresses().Informer().AddEventHandler(handlers)
+
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	health.MarkInformerSynced()
+
+	fullResyncInterval := defaultFullResyncInterval
+	if options.FullResyncInterval != "" {
+		parsed, err := time.ParseDuration(options.FullResyncInterval)
+		if err != nil {
+			log.Fatal().Str("full_resync_interval", options.FullResyncInterval).Err(err).Msg("invalid FULL_RESYNC_INTERVAL")
+		}
+		fullResyncInterval = parsed
+	}
+
+	ticker := time.NewTicker(fullResyncInterval)
+	defer ticker.Stop()
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				enqueueSync(queue)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	go runSyncWorker(queue, resync)
+
+	<-stop
+}
+
+func nodeIsReady(node *core_v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == core_v1.NodeReady && condition.Status == core_v1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	networking_v1 "k8s.io/api/networking/v1"
+)
+
+// ingressHostnames returns every host declared across ing's rules.
+func ingressHostnames(ing *networking_v1.Ingress) []string {
+	var hosts []string
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+	return hosts
+}
+
+// ingressAddresses returns the IPs assigned to ing's LoadBalancer status.
+func ingressAddresses(ing *networking_v1.Ingress) []string {
+	var ips []string
+	for _, lbIngress := range ing.Status.LoadBalancer.Ingress {
+		if lbIngress.IP != "" {
+			ips = append(ips, lbIngress.IP)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/ineentho/kubernetes-gandi-sync/metrics"
+)
+
+const (
+	defaultLeaseName      = "kubernetes-gandi-sync-leader"
+	defaultLeaseNamespace = "default"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// runWithLeaderElection blocks forever, running onStartedLeading whenever
+// this replica holds the leaseName/leaseNamespace Lease and stopping it
+// again as soon as the lease is lost. Losing the lease doesn't end the
+// process: the replica stays hot and re-enters the election, ready to
+// take over again.
+func runWithLeaderElection(client kubernetes.Interface, leaseName, leaseNamespace string, onStartedLeading func(ctx context.Context)) error {
+	identity, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to determine pod identity: %w", err)
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: leaseNamespace,
+		},
+		Client: client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	for {
+		leaderelection.RunOrDie(context.Background(), leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			ReleaseOnCancel: true,
+			LeaseDuration:   leaseDuration,
+			RenewDeadline:   renewDeadline,
+			RetryPeriod:     retryPeriod,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					log.Info().Str("identity", identity).Msg("acquired leadership")
+					metrics.IsLeader.Set(1)
+					onStartedLeading(ctx)
+				},
+				OnStoppedLeading: func() {
+					log.Info().Str("identity", identity).Msg("lost leadership")
+					metrics.IsLeader.Set(0)
+				},
+				OnNewLeader: func(currentIdentity string) {
+					if currentIdentity != identity {
+						log.Info().Str("leader", currentIdentity).Msg("observed new leader")
+					}
+				},
+			},
+		})
+	}
+}
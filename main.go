@@ -1,63 +1,129 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/fnv"
 	"os"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"github.com/tiramiseb/go-gandi-livedns"
-	core_v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/cache"
+
+	"github.com/ineentho/kubernetes-gandi-sync/metrics"
+	"github.com/ineentho/kubernetes-gandi-sync/provider"
+	"github.com/ineentho/kubernetes-gandi-sync/registry"
 )
 
+// zone is the zone used to build the fallback Config from DNS_NAMES when
+// no CONFIG_FILE is set.
+const zone = "textbrawlers.com"
+
+const defaultOwnerID = "kubernetes-gandi-sync"
+
+var dryRun = flag.Bool("dry-run", false, "log the changes that would be applied instead of writing them")
+
+// defaultFullResyncInterval is how often the controller re-applies its
+// desired state even without any Node/Service/Ingress event, correcting
+// DNS drift caused by changes made outside Kubernetes.
+const defaultFullResyncInterval = 5 * time.Minute
+
+// defaultMetricsAddr is where /metrics, /healthz and /readyz are served
+// when METRICS_ADDR isn't set.
+const defaultMetricsAddr = ":8080"
+
 var options = struct {
-	CloudflareAPIEmail string
-	CloudflareAPIKey   string
+	DNSProvider        string
 	DNSNames           string
 	NodeSelector       string
-	LivednsKey         string
+	ConfigFile         string
 	HumanLogs          bool
+	OwnerID            string
+	TXTPrefix          string
+	FullResyncInterval string
+	MetricsAddr        string
+
+	LeaderElection bool
+	LeaseName      string
+	LeaseNamespace string
+
+	ProviderConfig provider.Config
 }{
-	CloudflareAPIEmail: os.Getenv("CF_API_EMAIL"),
-	CloudflareAPIKey:   os.Getenv("CF_API_KEY"),
+	DNSProvider:        os.Getenv("DNS_PROVIDER"),
 	DNSNames:           os.Getenv("DNS_NAMES"),
 	NodeSelector:       os.Getenv("NODE_SELECTOR"),
-	LivednsKey:         os.Getenv("GANDI_LIVEDNS_KEY"),
+	ConfigFile:         os.Getenv("CONFIG_FILE"),
 	HumanLogs:          os.Getenv("HUMAN_LOGS") != "",
+	OwnerID:            os.Getenv("OWNER_ID"),
+	TXTPrefix:          os.Getenv("TXT_PREFIX"),
+	FullResyncInterval: os.Getenv("FULL_RESYNC_INTERVAL"),
+	MetricsAddr:        os.Getenv("METRICS_ADDR"),
+
+	LeaderElection: os.Getenv("LEADER_ELECTION") == "true",
+	LeaseName:      os.Getenv("LEASE_NAME"),
+	LeaseNamespace: os.Getenv("LEASE_NAMESPACE"),
+
+	ProviderConfig: provider.Config{
+		GandiLivednsKey:        os.Getenv("GANDI_LIVEDNS_KEY"),
+		CloudflareAPIEmail:     os.Getenv("CF_API_EMAIL"),
+		CloudflareAPIKey:       os.Getenv("CF_API_KEY"),
+		Route53AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		Route53SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		Route53Region:          os.Getenv("AWS_REGION"),
+		GoDaddyAPIKey:          os.Getenv("GODADDY_API_KEY"),
+		GoDaddyAPISecret:       os.Getenv("GODADDY_API_SECRET"),
+	},
 }
 
 func main() {
+	flag.Parse()
+
 	if options.HumanLogs {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
-	if options.LivednsKey == "" {
-		log.Fatal().Msg("LIVEDNS_KEY is required")
+	var cfg *Config
+	var err error
+	if options.ConfigFile != "" {
+		cfg, err = loadConfig(options.ConfigFile)
+	} else {
+		cfg, err = defaultConfig(options.DNSNames, options.NodeSelector)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid configuration")
 		os.Exit(1)
 	}
 
-	dnsNames := strings.Split(options.DNSNames, ",")
-	if len(dnsNames) == 1 && dnsNames[0] == "" {
-		log.Fatal().Msg("DNS_NAMES is required")
+	ownerID := options.OwnerID
+	if ownerID == "" {
+		ownerID = defaultOwnerID
+	}
+
+	dnsProvider, err := provider.New(provider.Name(options.DNSProvider), options.ProviderConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create dns provider")
 		os.Exit(1)
 	}
 
-	cfg, err := rest.InClusterConfig()
+	txtRegistry := &registry.TXTRegistry{
+		Provider:  dnsProvider,
+		OwnerID:   ownerID,
+		TXTPrefix: options.TXTPrefix,
+		DryRun:    *dryRun,
+	}
+
+	kubeCfg, err := rest.InClusterConfig()
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not create cluster config")
 		os.Exit(1)
 	}
 
-	client, err := kubernetes.NewForConfig(cfg)
+	client, err := kubernetes.NewForConfig(kubeCfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not create kubernetes client")
 		os.Exit(1)
@@ -66,100 +132,54 @@ func main() {
 	stop := make(chan struct{})
 	defer close(stop)
 
-	nodeSelector := labels.NewSelector()
-	if options.NodeSelector != "" {
-		selector, err := labels.Parse(options.NodeSelector)
-		if err != nil {
-			log.Fatal().Str("node_selector", options.NodeSelector).Err(err).Msg("node selector is invalid")
-			os.Exit(1)
-		} else {
-			nodeSelector = selector
-		}
+	health := &metrics.Health{}
+	metricsAddr := options.MetricsAddr
+	if metricsAddr == "" {
+		metricsAddr = defaultMetricsAddr
 	}
-
-	factory := informers.NewSharedInformerFactory(client, time.Minute)
-	lister := factory.Core().V1().Nodes().Lister()
-	var lastIPs []string
-	resync := func() {
-		log.Debug().Msg("resyncing")
-		nodes, err := lister.List(nodeSelector)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to list nodes")
+	go func() {
+		if err := metrics.Serve(metricsAddr, health); err != nil {
+			log.Fatal().Err(err).Msg("metrics server failed")
 		}
+	}()
 
-		var ips []string
-		for _, node := range nodes {
-			if nodeIsReady(node) {
-				for _, addr := range node.Status.Addresses {
-					if addr.Type == core_v1.NodeExternalIP {
-						ips = append(ips, addr.Address)
-					}
-				}
-			}
-		}
-
-		sort.Strings(ips)
-		if strings.Join(ips, ",") == strings.Join(lastIPs, ",") {
-			log.Debug().Strs("ips", ips).Msg("no change detected")
-			return
-		} else {
-			log.Info().Strs("ips", ips).Strs("last_ips", lastIPs).Msg("new ips detected")
-		}
-		lastIPs = ips
+	if !options.LeaderElection {
+		metrics.IsLeader.Set(1)
+		runController(stop, client, cfg, txtRegistry, health)
+		return
+	}
 
-		err = sync(ips, dnsNames, options.LivednsKey)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to sync")
-		}
+	leaseName := options.LeaseName
+	if leaseName == "" {
+		leaseName = defaultLeaseName
+	}
+	leaseNamespace := options.LeaseNamespace
+	if leaseNamespace == "" {
+		leaseNamespace = defaultLeaseNamespace
 	}
 
-	informer := factory.Core().V1().Nodes().Informer()
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			resync()
-		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			resync()
-		},
-		DeleteFunc: func(obj interface{}) {
-			resync()
-		},
+	err = runWithLeaderElection(client, leaseName, leaseNamespace, func(ctx context.Context) {
+		runController(ctx.Done(), client, cfg, txtRegistry, health)
 	})
-	informer.Run(stop)
-
-	select {}
-}
-
-func nodeIsReady(node *core_v1.Node) bool {
-	for _, condition := range node.Status.Conditions {
-		if condition.Type == core_v1.NodeReady && condition.Status == core_v1.ConditionTrue {
-			return true
-		}
+	if err != nil {
+		log.Fatal().Err(err).Msg("leader election failed")
 	}
-
-	return false
 }
 
-func sync(ips []string, dnsNames []string, livednsKey string) error {
-	gandiClient := gandi.New(livednsKey, "")
-
-	var records = []gandi.ZoneRecord{}
-
-	for _, dnsName := range dnsNames {
-		records = append(records, gandi.ZoneRecord{
-			RrsetType:   "A",
-			RrsetTTL:    300,
-			RrsetName:   dnsName,
-			RrsetValues: ips,
-		})
-	}
-
-	_, err := gandiClient.ChangeDomainRecords("textbrawlers.com", records)
+// selectorHash returns a short, stable identifier for a node selector, used
+// to tag the TXT ownership records this controller writes.
+func selectorHash(selector string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(selector))
+	return fmt.Sprintf("%x", h.Sum32())
+}
 
-	if err != nil {
+// sync applies the given records via txtRegistry.
+func sync(ctx context.Context, txtRegistry *registry.TXTRegistry, records []registry.ManagedRecord) error {
+	if err := txtRegistry.ApplyRecords(ctx, records); err != nil {
 		return errors.Wrap(err, "failed to update zone records")
 	}
 
-	log.Info().Strs("dns_names", dnsNames).Strs("ips", ips).Msg("zone records updated")
+	log.Info().Int("records", len(records)).Msg("zone records updated")
 	return nil
 }
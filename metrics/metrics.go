@@ -0,0 +1,106 @@
+// Package metrics exposes the Prometheus collectors this controller
+// publishes, plus the HTTP server serving /metrics and the /healthz and
+// /readyz endpoints used for Kubernetes probes.
+package metrics
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// SyncTotal counts reconcile attempts by outcome ("success"/"error").
+	SyncTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sync_total",
+		Help: "Total number of reconcile attempts, by result.",
+	}, []string{"result"})
+
+	// SyncDuration measures how long a reconcile takes end to end.
+	SyncDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "sync_duration_seconds",
+		Help: "Time taken to complete a reconcile.",
+	})
+
+	// DNSAPIRequestsTotal counts calls made to a DNS provider's API.
+	DNSAPIRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dns_api_requests_total",
+		Help: "Total number of requests made to the DNS provider API, by provider, operation and result code.",
+	}, []string{"provider", "op", "code"})
+
+	// ManagedRecords is the number of DNS records this controller is
+	// currently responsible for.
+	ManagedRecords = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "managed_records",
+		Help: "Number of DNS records currently managed by this controller.",
+	})
+
+	// LastSuccessfulSync is the unix timestamp of the last reconcile that
+	// completed without error.
+	LastSuccessfulSync = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "last_successful_sync_timestamp_seconds",
+		Help: "Unix timestamp of the last successful reconcile.",
+	})
+
+	// PublishedIPs is the number of addresses currently published for a
+	// given hostname.
+	PublishedIPs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "published_ips",
+		Help: "Number of IP addresses currently published for a hostname.",
+	}, []string{"hostname"})
+
+	// IsLeader is 1 if this replica currently holds the leader election
+	// lease, 0 otherwise. Always 1 when leader election is disabled.
+	IsLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "leader_election_status",
+		Help: "1 if this replica is the current leader, 0 otherwise.",
+	})
+)
+
+// Health tracks the two conditions readiness depends on: the informer
+// caches have synced, and at least one reconcile has completed
+// successfully.
+type Health struct {
+	informerSynced int32
+	everSynced     int32
+}
+
+// MarkInformerSynced records that the informer caches have synced.
+func (h *Health) MarkInformerSynced() {
+	atomic.StoreInt32(&h.informerSynced, 1)
+}
+
+// MarkSynced records that at least one reconcile has succeeded.
+func (h *Health) MarkSynced() {
+	atomic.StoreInt32(&h.everSynced, 1)
+}
+
+// Ready reports whether the controller is ready to serve traffic: the
+// informer caches are synced and a first reconcile has gone through.
+func (h *Health) Ready() bool {
+	return atomic.LoadInt32(&h.informerSynced) == 1 && atomic.LoadInt32(&h.everSynced) == 1
+}
+
+// Serve starts an HTTP server on addr exposing /metrics, /healthz and
+// /readyz. It blocks until the server stops.
+func Serve(addr string, health *Health) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !health.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Info().Str("addr", addr).Msg("starting metrics server")
+	return http.ListenAndServe(addr, mux)
+}
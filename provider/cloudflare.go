@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/pkg/errors"
+)
+
+// cloudflareProvider backs Provider with Cloudflare DNS.
+type cloudflareProvider struct {
+	client *cloudflare.API
+}
+
+func newCloudflareProvider(apiEmail, apiKey string) (*cloudflareProvider, error) {
+	client, err := cloudflare.New(apiKey, apiEmail)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cloudflare client")
+	}
+	return &cloudflareProvider{client: client}, nil
+}
+
+func (p *cloudflareProvider) Records(ctx context.Context, zone string) ([]Record, error) {
+	zoneID, err := p.client.ZoneIDByName(zone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve zone id for %s", zone)
+	}
+
+	var records []Record
+	for _, t := range []RecordType{A, AAAA, TXT} {
+		cfRecords, err := p.client.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Type: string(t)})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s records for %s", t, zone)
+		}
+		byName := map[string][]string{}
+		for _, r := range cfRecords {
+			name := recordApexName(zone, r.Name)
+			byName[name] = append(byName[name], r.Content)
+		}
+		for name, values := range byName {
+			records = append(records, Record{Zone: zone, Name: name, Type: t, Values: values})
+		}
+	}
+
+	return records, nil
+}
+
+func (p *cloudflareProvider) ApplyRecords(ctx context.Context, records []Record) error {
+	for _, r := range records {
+		zoneID, err := p.client.ZoneIDByName(r.Zone)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve zone id for %s", r.Zone)
+		}
+
+		name := recordFQDN(r.Zone, r.Name)
+
+		existing, err := p.client.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Type: string(r.Type), Name: name})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list existing %s records for %s", r.Type, name)
+		}
+		for _, e := range existing {
+			if err := p.client.DeleteDNSRecord(ctx, zoneID, e.ID); err != nil {
+				return errors.Wrapf(err, "failed to delete stale %s record for %s", r.Type, name)
+			}
+		}
+
+		for _, value := range r.Values {
+			_, err := p.client.CreateDNSRecord(ctx, zoneID, cloudflare.DNSRecord{
+				Type:    string(r.Type),
+				Name:    name,
+				Content: value,
+				TTL:     r.TTL,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s record for %s", r.Type, name)
+			}
+		}
+	}
+
+	return nil
+}
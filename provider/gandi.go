@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	gandi "github.com/tiramiseb/go-gandi-livedns"
+)
+
+// gandiProvider backs Provider with Gandi LiveDNS, the original (and
+// still default) backend for this controller.
+type gandiProvider struct {
+	client *gandi.Gandi
+}
+
+func newGandiProvider(livednsKey string) *gandiProvider {
+	return &gandiProvider{client: gandi.New(livednsKey, "")}
+}
+
+func (p *gandiProvider) Records(ctx context.Context, zone string) ([]Record, error) {
+	zoneRecords, err := p.client.ListDomainRecords(zone)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list zone records")
+	}
+
+	var records []Record
+	for _, zr := range zoneRecords {
+		switch RecordType(zr.RrsetType) {
+		case A, AAAA, TXT:
+			records = append(records, Record{
+				Zone:   zone,
+				Name:   zr.RrsetName,
+				Type:   RecordType(zr.RrsetType),
+				TTL:    zr.RrsetTTL,
+				Values: zr.RrsetValues,
+			})
+		}
+	}
+
+	return records, nil
+}
+
+func (p *gandiProvider) ApplyRecords(ctx context.Context, records []Record) error {
+	byZone := map[string][]gandi.ZoneRecord{}
+	for _, r := range records {
+		byZone[r.Zone] = append(byZone[r.Zone], gandi.ZoneRecord{
+			RrsetType:   string(r.Type),
+			RrsetTTL:    r.TTL,
+			RrsetName:   r.Name,
+			RrsetValues: r.Values,
+		})
+	}
+
+	for zone, zoneRecords := range byZone {
+		if _, err := p.client.ChangeDomainRecords(zone, zoneRecords); err != nil {
+			return errors.Wrapf(err, "failed to update zone records for %s", zone)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const godaddyAPIBase = "https://api.godaddy.com/v1"
+
+// goDaddyProvider backs Provider with the GoDaddy Domains API. GoDaddy has
+// no official Go SDK, so this talks to the REST API directly.
+type goDaddyProvider struct {
+	apiKey    string
+	apiSecret string
+	http      *http.Client
+}
+
+func newGoDaddyProvider(apiKey, apiSecret string) *goDaddyProvider {
+	return &goDaddyProvider{apiKey: apiKey, apiSecret: apiSecret, http: http.DefaultClient}
+}
+
+type godaddyRecord struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl"`
+}
+
+func (p *goDaddyProvider) do(ctx context.Context, method, url string, body interface{}) (*http.Response, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request body")
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("sso-key %s:%s", p.apiKey, p.apiSecret))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "request failed")
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("godaddy api returned status %d for %s %s", resp.StatusCode, method, url)
+	}
+	return resp, nil
+}
+
+func (p *goDaddyProvider) Records(ctx context.Context, zone string) ([]Record, error) {
+	var records []Record
+	for _, t := range []RecordType{A, AAAA, TXT} {
+		url := fmt.Sprintf("%s/domains/%s/records/%s", godaddyAPIBase, zone, t)
+		resp, err := p.do(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s records for %s", t, zone)
+		}
+		defer resp.Body.Close()
+
+		var gdRecords []godaddyRecord
+		if err := json.NewDecoder(resp.Body).Decode(&gdRecords); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s records for %s", t, zone)
+		}
+
+		byName := map[string][]string{}
+		ttlByName := map[string]int{}
+		for _, r := range gdRecords {
+			byName[r.Name] = append(byName[r.Name], r.Data)
+			ttlByName[r.Name] = r.TTL
+		}
+		for name, values := range byName {
+			records = append(records, Record{Zone: zone, Name: name, Type: t, TTL: ttlByName[name], Values: values})
+		}
+	}
+
+	return records, nil
+}
+
+func (p *goDaddyProvider) ApplyRecords(ctx context.Context, records []Record) error {
+	for _, r := range records {
+		var gdRecords []godaddyRecord
+		for _, value := range r.Values {
+			gdRecords = append(gdRecords, godaddyRecord{Type: string(r.Type), Name: r.Name, Data: value, TTL: r.TTL})
+		}
+
+		url := fmt.Sprintf("%s/domains/%s/records/%s/%s", godaddyAPIBase, r.Zone, r.Type, r.Name)
+		resp, err := p.do(ctx, http.MethodPut, url, gdRecords)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update %s record for %s", r.Type, r.Name)
+		}
+		resp.Body.Close()
+	}
+
+	return nil
+}
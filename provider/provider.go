@@ -0,0 +1,159 @@
+// Package provider defines the DNS backend abstraction used by
+// kubernetes-gandi-sync to publish node/service addresses to a zone.
+//
+// Each backend (Gandi, Cloudflare, Route53, GoDaddy, ...) implements
+// Provider in its own file so that adding a new backend touches only
+// that one file plus the selection switch in New.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ineentho/kubernetes-gandi-sync/metrics"
+)
+
+// RecordType is the DNS RRset type a Record represents.
+type RecordType string
+
+const (
+	// A is an IPv4 address record.
+	A RecordType = "A"
+	// AAAA is an IPv6 address record.
+	AAAA RecordType = "AAAA"
+	// TXT is a text record, used by the ownership registry.
+	TXT RecordType = "TXT"
+)
+
+// Record is a single RRset to be applied to a zone. Values holds every
+// value of the set (e.g. all node IPs for a round-robin A record).
+type Record struct {
+	Zone   string
+	Name   string
+	Type   RecordType
+	TTL    int
+	Values []string
+}
+
+// Provider is implemented by each supported DNS backend. Implementations
+// are expected to replace the RRset named by each Record wholesale, the
+// same semantics as Gandi's ChangeDomainRecords.
+type Provider interface {
+	// Records returns the records currently present for the given zone,
+	// restricted to the types this controller manages (A/AAAA/TXT).
+	Records(ctx context.Context, zone string) ([]Record, error)
+	// ApplyRecords writes the given records to their zones, replacing
+	// any existing RRset with the same name and type.
+	ApplyRecords(ctx context.Context, records []Record) error
+}
+
+// Name identifies a Provider implementation, as selected via the
+// DNS_PROVIDER env var.
+type Name string
+
+const (
+	Gandi      Name = "gandi"
+	Cloudflare Name = "cloudflare"
+	Route53    Name = "route53"
+	GoDaddy    Name = "godaddy"
+)
+
+// Config holds the credentials needed by every supported provider.
+// Only the fields relevant to the selected Name need be set.
+type Config struct {
+	GandiLivednsKey string
+
+	CloudflareAPIEmail string
+	CloudflareAPIKey   string
+
+	Route53AccessKeyID     string
+	Route53SecretAccessKey string
+	Route53Region          string
+
+	GoDaddyAPIKey    string
+	GoDaddyAPISecret string
+}
+
+// New constructs the Provider selected by name using cfg, instrumented
+// with the dns_api_requests_total metric.
+func New(name Name, cfg Config) (Provider, error) {
+	var p Provider
+	var err error
+
+	switch name {
+	case Gandi, "":
+		if cfg.GandiLivednsKey == "" {
+			return nil, fmt.Errorf("provider %q: GANDI_LIVEDNS_KEY is required", Gandi)
+		}
+		p = newGandiProvider(cfg.GandiLivednsKey)
+		name = Gandi
+	case Cloudflare:
+		if cfg.CloudflareAPIEmail == "" || cfg.CloudflareAPIKey == "" {
+			return nil, fmt.Errorf("provider %q: CF_API_EMAIL and CF_API_KEY are required", Cloudflare)
+		}
+		p, err = newCloudflareProvider(cfg.CloudflareAPIEmail, cfg.CloudflareAPIKey)
+	case Route53:
+		p, err = newRoute53Provider(cfg)
+	case GoDaddy:
+		if cfg.GoDaddyAPIKey == "" || cfg.GoDaddyAPISecret == "" {
+			return nil, fmt.Errorf("provider %q: GODADDY_API_KEY and GODADDY_API_SECRET are required", GoDaddy)
+		}
+		p = newGoDaddyProvider(cfg.GoDaddyAPIKey, cfg.GoDaddyAPISecret)
+	default:
+		return nil, fmt.Errorf("unknown DNS_PROVIDER %q", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedProvider{name: name, inner: p}, nil
+}
+
+// instrumentedProvider wraps a Provider to record dns_api_requests_total
+// for every call made to the backend.
+type instrumentedProvider struct {
+	name  Name
+	inner Provider
+}
+
+func (p *instrumentedProvider) Records(ctx context.Context, zone string) ([]Record, error) {
+	records, err := p.inner.Records(ctx, zone)
+	metrics.DNSAPIRequestsTotal.WithLabelValues(string(p.name), "list", resultCode(err)).Inc()
+	return records, err
+}
+
+func (p *instrumentedProvider) ApplyRecords(ctx context.Context, records []Record) error {
+	err := p.inner.ApplyRecords(ctx, records)
+	metrics.DNSAPIRequestsTotal.WithLabelValues(string(p.name), "apply", resultCode(err)).Inc()
+	return err
+}
+
+func resultCode(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// recordFQDN resolves a Record's zone-relative name to the full record
+// name. Gandi and GoDaddy use "@" to mean the zone root natively;
+// providers that expect the full record name instead (Cloudflare,
+// Route53) should call this rather than using Name directly, so a host
+// matching the zone root isn't sent as the literal label "@".
+func recordFQDN(zone, name string) string {
+	if name == "@" {
+		return zone
+	}
+	return name + "." + zone
+}
+
+// recordApexName is recordFQDN's inverse, used when reading records back
+// from a provider that reports the zone root using its full name rather
+// than "@", so Record.Name stays consistent regardless of provider.
+func recordApexName(zone, name string) string {
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
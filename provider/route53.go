@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+)
+
+// route53Provider backs Provider with AWS Route53.
+type route53Provider struct {
+	client *route53.Client
+}
+
+func newRoute53Provider(cfg Config) (*route53Provider, error) {
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if cfg.Route53Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Route53Region))
+	}
+	if cfg.Route53AccessKeyID != "" && cfg.Route53SecretAccessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.Route53AccessKeyID, cfg.Route53SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load aws config")
+	}
+
+	return &route53Provider{client: route53.NewFromConfig(awsCfg)}, nil
+}
+
+func (p *route53Provider) hostedZoneID(ctx context.Context, zone string) (string, error) {
+	out, err := p.client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(zone),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to look up hosted zone for %s", zone)
+	}
+	if len(out.HostedZones) == 0 {
+		return "", fmt.Errorf("no hosted zone found for %s", zone)
+	}
+	return aws.ToString(out.HostedZones[0].Id), nil
+}
+
+func (p *route53Provider) Records(ctx context.Context, zone string) ([]Record, error) {
+	zoneID, err := p.hostedZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := p.client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list resource record sets for %s", zone)
+	}
+
+	var records []Record
+	for _, rrset := range out.ResourceRecordSets {
+		t := RecordType(rrset.Type)
+		if t != A && t != AAAA && t != TXT {
+			continue
+		}
+		var values []string
+		for _, rr := range rrset.ResourceRecords {
+			values = append(values, aws.ToString(rr.Value))
+		}
+		records = append(records, Record{
+			Zone:   zone,
+			Name:   recordApexName(zone, strings.TrimSuffix(aws.ToString(rrset.Name), ".")),
+			Type:   t,
+			TTL:    int(aws.ToInt64(rrset.TTL)),
+			Values: values,
+		})
+	}
+
+	return records, nil
+}
+
+func (p *route53Provider) ApplyRecords(ctx context.Context, records []Record) error {
+	byZone := map[string][]Record{}
+	for _, r := range records {
+		byZone[r.Zone] = append(byZone[r.Zone], r)
+	}
+
+	for zone, zoneRecords := range byZone {
+		zoneID, err := p.hostedZoneID(ctx, zone)
+		if err != nil {
+			return err
+		}
+
+		var changes []r53types.Change
+		for _, r := range zoneRecords {
+			var resourceRecords []r53types.ResourceRecord
+			for _, value := range r.Values {
+				resourceRecords = append(resourceRecords, r53types.ResourceRecord{Value: aws.String(value)})
+			}
+			changes = append(changes, r53types.Change{
+				Action: r53types.ChangeActionUpsert,
+				ResourceRecordSet: &r53types.ResourceRecordSet{
+					Name:            aws.String(recordFQDN(r.Zone, r.Name)),
+					Type:            r53types.RRType(r.Type),
+					TTL:             aws.Int64(int64(r.TTL)),
+					ResourceRecords: resourceRecords,
+				},
+			})
+		}
+
+		_, err = p.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(zoneID),
+			ChangeBatch:  &r53types.ChangeBatch{Changes: changes},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update resource record sets for %s", zone)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+	networking_v1 "k8s.io/api/networking/v1"
+
+	"github.com/ineentho/kubernetes-gandi-sync/provider"
+	"github.com/ineentho/kubernetes-gandi-sync/registry"
+)
+
+// splitIPs separates addresses into IPv4 and IPv6 values.
+func splitIPs(addresses []string) (ipv4, ipv6 []string) {
+	for _, address := range addresses {
+		if parsed := net.ParseIP(address); parsed != nil && parsed.To4() == nil {
+			ipv6 = append(ipv6, address)
+		} else {
+			ipv4 = append(ipv4, address)
+		}
+	}
+	return ipv4, ipv6
+}
+
+// buildRecords turns a desired name/addresses pair into the A and/or AAAA
+// ManagedRecords needed to publish it, tagged with resource for the TXT
+// ownership registry.
+func buildRecords(zone, name string, addresses []string, resource string) []registry.ManagedRecord {
+	ipv4, ipv6 := splitIPs(addresses)
+
+	var records []registry.ManagedRecord
+	if len(ipv4) > 0 {
+		records = append(records, registry.ManagedRecord{
+			Record:   provider.Record{Zone: zone, Name: name, Type: provider.A, TTL: defaultTTL, Values: ipv4},
+			Resource: resource,
+		})
+	}
+	if len(ipv6) > 0 {
+		records = append(records, registry.ManagedRecord{
+			Record:   provider.Record{Zone: zone, Name: name, Type: provider.AAAA, TTL: defaultTTL, Values: ipv6},
+			Resource: resource,
+		})
+	}
+	return records
+}
+
+// recordsForRule is buildRecords for a HostnameRule: it honors the rule's
+// TTL and, if Type is pinned to A or AAAA, only publishes that family even
+// if addresses of the other family are present.
+func recordsForRule(rule HostnameRule, addresses []string, resource string) []registry.ManagedRecord {
+	ipv4, ipv6 := splitIPs(addresses)
+	wantA := rule.Type == "" || rule.Type == "A"
+	wantAAAA := rule.Type == "" || rule.Type == "AAAA"
+
+	var records []registry.ManagedRecord
+	if wantA && len(ipv4) > 0 {
+		records = append(records, registry.ManagedRecord{
+			Record:   provider.Record{Zone: rule.Zone, Name: rule.Name, Type: provider.A, TTL: rule.TTL, Values: ipv4},
+			Resource: resource,
+		})
+	}
+	if wantAAAA && len(ipv6) > 0 {
+		records = append(records, registry.ManagedRecord{
+			Record:   provider.Record{Zone: rule.Zone, Name: rule.Name, Type: provider.AAAA, TTL: rule.TTL, Values: ipv6},
+			Resource: resource,
+		})
+	}
+	return records
+}
+
+// zoneForHostname picks the configured zone whose domain hostname falls
+// under (the longest matching suffix), for sources like Services and
+// Ingresses that declare a full hostname rather than a zone+name pair. It
+// returns ("", "") if hostname doesn't fall under any configured zone.
+func zoneForHostname(hostname string, zones []string) (zoneName, recordName string) {
+	var best string
+	for _, z := range zones {
+		if (hostname == z || strings.HasSuffix(hostname, "."+z)) && len(z) > len(best) {
+			best = z
+		}
+	}
+	if best == "" {
+		return "", ""
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(hostname, best), ".")
+	if name == "" {
+		name = "@"
+	}
+	return best, name
+}
+
+// serviceAndIngressRecords builds the ManagedRecords requested by
+// annotated Services and host-bearing Ingresses, resolving each hostname
+// against zones (the zones configured via HostnameRules).
+func serviceAndIngressRecords(zones []string, nodeIPs []string, services []*core_v1.Service, ingresses []*networking_v1.Ingress) []registry.ManagedRecord {
+	var records []registry.ManagedRecord
+
+	for _, svc := range services {
+		if !serviceIsSupported(svc) {
+			continue
+		}
+		hostname := serviceHostname(svc)
+		if hostname == "" {
+			continue
+		}
+		zoneName, recordName := zoneForHostname(hostname, zones)
+		if zoneName == "" {
+			continue
+		}
+		addresses := serviceAddresses(svc, nodeIPs)
+		if len(addresses) == 0 {
+			continue
+		}
+		resource := fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name)
+		records = append(records, buildRecords(zoneName, recordName, addresses, resource)...)
+	}
+
+	for _, ing := range ingresses {
+		addresses := ingressAddresses(ing)
+		if len(addresses) == 0 {
+			continue
+		}
+		resource := fmt.Sprintf("ingress/%s/%s", ing.Namespace, ing.Name)
+		for _, hostname := range ingressHostnames(ing) {
+			zoneName, recordName := zoneForHostname(hostname, zones)
+			if zoneName == "" {
+				continue
+			}
+			records = append(records, buildRecords(zoneName, recordName, addresses, resource)...)
+		}
+	}
+
+	return records
+}
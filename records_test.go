@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestZoneForHostname(t *testing.T) {
+	zones := []string{"example.com", "api.example.com"}
+
+	cases := []struct {
+		hostname   string
+		wantZone   string
+		wantRecord string
+	}{
+		{hostname: "example.com", wantZone: "example.com", wantRecord: "@"},
+		{hostname: "www.example.com", wantZone: "example.com", wantRecord: "www"},
+		{hostname: "v1.api.example.com", wantZone: "api.example.com", wantRecord: "v1"},
+		{hostname: "other.org", wantZone: "", wantRecord: ""},
+	}
+
+	for _, c := range cases {
+		gotZone, gotRecord := zoneForHostname(c.hostname, zones)
+		if gotZone != c.wantZone || gotRecord != c.wantRecord {
+			t.Errorf("zoneForHostname(%q) = (%q, %q), want (%q, %q)", c.hostname, gotZone, gotRecord, c.wantZone, c.wantRecord)
+		}
+	}
+}
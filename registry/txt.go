@@ -0,0 +1,139 @@
+// Package registry layers an ownership registry on top of a
+// provider.Provider so that reconciles never clobber records the
+// controller doesn't own, following the pattern external-dns calls
+// --registry=txt.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ineentho/kubernetes-gandi-sync/provider"
+)
+
+const heritage = "kubernetes-gandi-sync"
+
+// TXTRegistry wraps a Provider. For every managed A/AAAA record it also
+// writes a sibling TXT record of the form
+// "heritage=kubernetes-gandi-sync,owner=<OwnerID>,resource=<resource>".
+// On apply, only records whose sibling TXT proves ownership (or that
+// don't exist yet) are touched; anything else already in the zone is
+// left alone.
+type TXTRegistry struct {
+	Provider  provider.Provider
+	OwnerID   string
+	TXTPrefix string
+	DryRun    bool
+}
+
+// txtName returns the name of the TXT sibling for recordName. The zone
+// apex marker "@" is special-cased: prepending TXTPrefix to it verbatim
+// would produce a literal label like "_prefix@" instead of a name under
+// the zone, so an apex record's sibling drops the "@" and is named after
+// the prefix alone (falling back to "@" itself when no prefix is set).
+func (r *TXTRegistry) txtName(recordName string) string {
+	if recordName == "@" {
+		if r.TXTPrefix == "" {
+			return "@"
+		}
+		return strings.TrimSuffix(r.TXTPrefix, ".")
+	}
+	return r.TXTPrefix + recordName
+}
+
+func (r *TXTRegistry) txtValue(resource string) string {
+	return fmt.Sprintf("heritage=%s,owner=%s,resource=%s", heritage, r.OwnerID, resource)
+}
+
+// ownedBy reports whether value was written by this registry's OwnerID.
+// value is parsed into its comma-separated "key=value" fields so that, for
+// example, an OwnerID of "foo" doesn't also match "foobar" or "foo-other".
+func (r *TXTRegistry) ownedBy(value string) bool {
+	fields := parseTXTValue(value)
+	return fields["heritage"] == heritage && fields["owner"] == r.OwnerID
+}
+
+// parseTXTValue splits a "heritage=...,owner=...,resource=..." TXT value
+// into its fields.
+func parseTXTValue(value string) map[string]string {
+	fields := map[string]string{}
+	for _, part := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		fields[key] = val
+	}
+	return fields
+}
+
+// ManagedRecord is a record this controller wants published, tagged with
+// the Kubernetes resource responsible for it (e.g. "node/<selector-hash>"
+// or "service/default/api"), which is recorded in its TXT sibling.
+type ManagedRecord struct {
+	provider.Record
+	Resource string
+}
+
+// ApplyRecords reconciles records (A/AAAA only; TXT siblings are derived
+// here) against the zones they belong to, skipping any name that already
+// exists in the zone without a TXT record proving this registry owns it.
+func (r *TXTRegistry) ApplyRecords(ctx context.Context, records []ManagedRecord) error {
+	byZone := map[string][]ManagedRecord{}
+	for _, rec := range records {
+		byZone[rec.Zone] = append(byZone[rec.Zone], rec)
+	}
+
+	var toApply []provider.Record
+	for zone, zoneRecords := range byZone {
+		existing, err := r.Provider.Records(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("failed to list existing records for %s: %w", zone, err)
+		}
+
+		txtByName := map[string]string{}
+		for _, e := range existing {
+			if e.Type == provider.TXT && len(e.Values) > 0 {
+				txtByName[e.Name] = e.Values[0]
+			}
+		}
+
+		foreign := map[string]bool{}
+		for _, e := range existing {
+			if e.Type == provider.TXT {
+				continue
+			}
+			if !r.ownedBy(txtByName[r.txtName(e.Name)]) {
+				foreign[e.Name] = true
+			}
+		}
+
+		for _, rec := range zoneRecords {
+			txt, hasTXT := txtByName[r.txtName(rec.Name)]
+			if foreign[rec.Name] || (hasTXT && !r.ownedBy(txt)) {
+				log.Warn().Str("zone", zone).Str("name", rec.Name).Msg("record exists and is not owned by this registry, skipping")
+				continue
+			}
+			toApply = append(toApply, rec.Record)
+			toApply = append(toApply, provider.Record{
+				Zone:   zone,
+				Name:   r.txtName(rec.Name),
+				Type:   provider.TXT,
+				TTL:    rec.TTL,
+				Values: []string{r.txtValue(rec.Resource)},
+			})
+		}
+	}
+
+	if r.DryRun {
+		for _, rec := range toApply {
+			log.Info().Str("zone", rec.Zone).Str("name", rec.Name).Str("type", string(rec.Type)).Strs("values", rec.Values).Msg("dry-run: would apply record")
+		}
+		return nil
+	}
+
+	return r.Provider.ApplyRecords(ctx, toApply)
+}
@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ineentho/kubernetes-gandi-sync/provider"
+)
+
+// fakeProvider is a minimal in-memory provider.Provider used to exercise
+// TXTRegistry without a real DNS backend.
+type fakeProvider struct {
+	records []provider.Record
+	applied []provider.Record
+}
+
+func (p *fakeProvider) Records(ctx context.Context, zone string) ([]provider.Record, error) {
+	var out []provider.Record
+	for _, r := range p.records {
+		if r.Zone == zone {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (p *fakeProvider) ApplyRecords(ctx context.Context, records []provider.Record) error {
+	p.applied = append(p.applied, records...)
+	return nil
+}
+
+func TestApplyRecordsSkipsForeignRecords(t *testing.T) {
+	fp := &fakeProvider{
+		records: []provider.Record{
+			{Zone: "example.com", Name: "mail", Type: provider.A, Values: []string{"10.0.0.9"}},
+		},
+	}
+	r := &TXTRegistry{Provider: fp, OwnerID: "gandi-sync"}
+
+	err := r.ApplyRecords(context.Background(), []ManagedRecord{
+		{Record: provider.Record{Zone: "example.com", Name: "mail", Type: provider.A, Values: []string{"1.2.3.4"}}, Resource: "node/abc"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyRecords returned error: %v", err)
+	}
+	if len(fp.applied) != 0 {
+		t.Fatalf("expected foreign record to be skipped, got %d applied records", len(fp.applied))
+	}
+}
+
+func TestApplyRecordsUpdatesOwnedRecords(t *testing.T) {
+	fp := &fakeProvider{
+		records: []provider.Record{
+			{Zone: "example.com", Name: "api", Type: provider.A, Values: []string{"10.0.0.1"}},
+			{Zone: "example.com", Name: "api", Type: provider.TXT, Values: []string{"heritage=kubernetes-gandi-sync,owner=gandi-sync,resource=node/abc"}},
+		},
+	}
+	r := &TXTRegistry{Provider: fp, OwnerID: "gandi-sync"}
+
+	err := r.ApplyRecords(context.Background(), []ManagedRecord{
+		{Record: provider.Record{Zone: "example.com", Name: "api", Type: provider.A, Values: []string{"10.0.0.2"}}, Resource: "node/abc"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyRecords returned error: %v", err)
+	}
+
+	var names []string
+	for _, rec := range fp.applied {
+		names = append(names, rec.Name+"/"+string(rec.Type))
+	}
+	sort.Strings(names)
+	want := []string{"api/A", "api/TXT"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("applied records = %v, want %v", names, want)
+	}
+}
+
+func TestApplyRecordsDoesNotAdoptSimilarOwnerPrefix(t *testing.T) {
+	fp := &fakeProvider{
+		records: []provider.Record{
+			{Zone: "example.com", Name: "www", Type: provider.A, Values: []string{"10.0.0.1"}},
+			{Zone: "example.com", Name: "www", Type: provider.TXT, Values: []string{"heritage=kubernetes-gandi-sync,owner=gandi-sync-other,resource=node/abc"}},
+		},
+	}
+	r := &TXTRegistry{Provider: fp, OwnerID: "gandi-sync"}
+
+	err := r.ApplyRecords(context.Background(), []ManagedRecord{
+		{Record: provider.Record{Zone: "example.com", Name: "www", Type: provider.A, Values: []string{"10.0.0.2"}}, Resource: "node/abc"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyRecords returned error: %v", err)
+	}
+	if len(fp.applied) != 0 {
+		t.Fatalf("expected record owned by a different, prefix-colliding OwnerID to be left alone, got %d applied records", len(fp.applied))
+	}
+}
+
+func TestTxtNameApex(t *testing.T) {
+	cases := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "", want: "@"},
+		{prefix: "_txt.", want: "_txt"},
+	}
+
+	for _, c := range cases {
+		r := &TXTRegistry{TXTPrefix: c.prefix}
+		if got := r.txtName("@"); got != c.want {
+			t.Errorf("txtName(%q) with prefix %q = %q, want %q", "@", c.prefix, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+)
+
+// hostnameAnnotation is set on a Service to request that its address be
+// published under the given DNS name, e.g. "api.example.com".
+const hostnameAnnotation = "gandi-sync/hostname"
+
+// serviceIsSupported reports whether svc is a type this controller can
+// publish DNS for.
+func serviceIsSupported(svc *core_v1.Service) bool {
+	switch svc.Spec.Type {
+	case core_v1.ServiceTypeLoadBalancer, core_v1.ServiceTypeNodePort:
+		return true
+	default:
+		return false
+	}
+}
+
+// serviceHostname returns the DNS hostname svc requests via
+// hostnameAnnotation, or "" if it isn't annotated.
+func serviceHostname(svc *core_v1.Service) string {
+	return svc.Annotations[hostnameAnnotation]
+}
+
+// serviceAddresses returns the addresses svc should publish: its
+// LoadBalancer ingress IPs if any have been assigned (e.g. by MetalLB),
+// otherwise the external IPs of its nodes for a NodePort service.
+func serviceAddresses(svc *core_v1.Service, nodeIPs []string) []string {
+	if isServiceIPSet(svc) {
+		var ips []string
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				ips = append(ips, ingress.IP)
+			}
+		}
+		if len(ips) > 0 {
+			return ips
+		}
+	}
+
+	if svc.Spec.Type == core_v1.ServiceTypeNodePort {
+		return nodeIPs
+	}
+
+	return nil
+}
+
+// isServiceIPSet reports whether svc's LoadBalancer status carries at
+// least one ingress address, mirroring the check kube-proxy and
+// external-dns use to know a LoadBalancer has been provisioned.
+func isServiceIPSet(svc *core_v1.Service) bool {
+	return svc.Spec.Type == core_v1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) > 0
+}
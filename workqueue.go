@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// syncKey is the single sentinel item enqueued by every informer event.
+// Since the controller always recomputes every record from scratch, a
+// burst of Node/Service/Ingress churn only needs to trigger one sync, not
+// one per object.
+const syncKey = "sync"
+
+// debounceInterval is how long enqueueSync waits before a sync actually
+// runs, so that a burst of events (e.g. a node flapping, or a rollout
+// touching many services at once) collapses into a single reconcile.
+const debounceInterval = 5 * time.Second
+
+// newSyncQueue returns a rate-limited workqueue used to debounce informer
+// events and back off exponentially when sync fails.
+func newSyncQueue() workqueue.RateLimitingInterface {
+	return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+}
+
+// enqueueSync schedules a debounced sync. It's safe to call repeatedly in
+// a tight loop; duplicate pending entries for syncKey collapse into one.
+func enqueueSync(queue workqueue.RateLimitingInterface) {
+	queue.AddAfter(syncKey, debounceInterval)
+}
+
+// runSyncWorker drains queue, calling resync for each item, until queue is
+// shut down. Failures are retried with the queue's exponential backoff.
+func runSyncWorker(queue workqueue.RateLimitingInterface, resync func() error) {
+	for {
+		key, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+
+		err := resync()
+		if err != nil {
+			log.Error().Err(err).Msg("sync failed, will retry with backoff")
+			queue.AddRateLimited(key)
+		} else {
+			queue.Forget(key)
+		}
+		queue.Done(key)
+	}
+}